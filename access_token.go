@@ -0,0 +1,61 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AccessToken is a short-lived Cloudflare Access token, exchanged for an
+// OIDC identity and sent to Access-protected origins via the
+// Cf-Access-Token header, mirroring the token cloudflared's carrier
+// package attaches to proxied connections.
+type AccessToken struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+func (t AccessToken) expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// AccessTokenStore mints or refreshes Cloudflare Access tokens on demand.
+// Implementations typically exchange an OIDC identity token or a service
+// token with Access's token endpoint and cache the result locally.
+type AccessTokenStore interface {
+	Token(ctx context.Context) (AccessToken, error)
+}
+
+// AccessTokenProvider is a CredentialProvider that attaches a Cloudflare
+// Access token minted by a local AccessTokenStore, refreshing it
+// transparently whenever it is missing or expired.
+type AccessTokenProvider struct {
+	store AccessTokenStore
+
+	mu    sync.Mutex
+	cache AccessToken
+}
+
+// NewAccessTokenProvider returns an AccessTokenProvider backed by store.
+func NewAccessTokenProvider(store AccessTokenStore) *AccessTokenProvider {
+	return &AccessTokenProvider{store: store}
+}
+
+// ApplyAuth implements CredentialProvider.
+func (p *AccessTokenProvider) ApplyAuth(ctx context.Context, req *http.Request) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cache.Value == "" || p.cache.expired(time.Now()) {
+		tok, err := p.store.Token(ctx)
+		if err != nil {
+			return &AuthError{Provider: "access-token", Err: fmt.Errorf("minting access token: %w", err)}
+		}
+		p.cache = tok
+	}
+
+	req.Header.Set("Cf-Access-Token", p.cache.Value)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Package cftest provides a record/replay HTTP test harness for exercising
+// the Cloudflare API surface without every contributor needing production
+// credentials. In record mode (-cf.record) it proxies requests to the real
+// API and saves the interactions to a cassette file; in replay mode, the
+// default, it serves those interactions back from an httptest.Server, in
+// request order per key, so pagination sequences replay correctly.
+package cftest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Method        string      `json:"method"`
+	Path          string      `json:"path"`
+	Query         string      `json:"query"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+	StatusCode    int         `json:"status_code"`
+	Header        http.Header `json:"header"`
+	Body          string      `json:"body"`
+}
+
+// Cassette is the on-disk recording of a sequence of API interactions.
+// Each key maps to an ordered slice of responses: a key with multiple
+// interactions is replayed in order, one per matching request, so a
+// paginated listing can be captured and replayed page by page.
+type Cassette struct {
+	Interactions map[string][]Interaction `json:"interactions"`
+}
+
+func newCassette() *Cassette {
+	return &Cassette{Interactions: make(map[string][]Interaction)}
+}
+
+// loadCassette reads a cassette from path. A missing file yields an empty
+// cassette so a fresh recording can be started.
+func loadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newCassette(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cftest: reading cassette: %w", err)
+	}
+
+	c := newCassette()
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("cftest: decoding cassette %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *Cassette) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cftest: encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cftest: creating cassette directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("cftest: writing cassette %s: %w", path, err)
+	}
+	return nil
+}
+
+// requestKey identifies a request by method, path, sorted query, and a
+// hash of its body, so replay can match a request back to the
+// interaction(s) recorded for it regardless of header or query-parameter
+// ordering.
+func requestKey(method, path, rawQuery string, body []byte) string {
+	values, _ := url.ParseQuery(rawQuery)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sortedQuery strings.Builder
+	for i, name := range names {
+		sort.Strings(values[name])
+		for j, v := range values[name] {
+			if i > 0 || j > 0 {
+				sortedQuery.WriteByte('&')
+			}
+			sortedQuery.WriteString(name)
+			sortedQuery.WriteByte('=')
+			sortedQuery.WriteString(v)
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("%s %s?%s#%x", method, path, sortedQuery.String(), sum)
+}
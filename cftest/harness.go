@@ -0,0 +1,165 @@
+package cftest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// record, set via -cf.record, switches every Harness from replay mode (the
+// default) to record mode.
+var record = flag.Bool("cf.record", false, "record cftest cassettes against the live Cloudflare API instead of replaying saved ones")
+
+// Harness is a record/replay HTTP test harness for the Cloudflare API.
+// Point a cloudflare.API's BaseURL at Harness.Server().URL to exercise it
+// against a saved fixture instead of a hand-rolled mux.HandleFunc
+// responder.
+type Harness struct {
+	t        testing.TB
+	path     string
+	cassette *Cassette
+	server   *httptest.Server
+
+	mu     sync.Mutex
+	cursor map[string]int
+}
+
+// New returns a Harness for name, replaying testdata/<name>.json unless
+// -cf.record is set. In record mode, requests are proxied to
+// upstreamBaseURL (typically https://api.cloudflare.com/client/v4) using
+// an API token read from the CF_API_TOKEN environment variable, and the
+// resulting cassette is written to testdata/<name>.json when the test
+// finishes.
+func New(t testing.TB, name, upstreamBaseURL string) *Harness {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".json")
+	cassette, err := loadCassette(path)
+	if err != nil {
+		t.Fatalf("cftest: %v", err)
+	}
+
+	h := &Harness{t: t, path: path, cassette: cassette, cursor: make(map[string]int)}
+
+	var handler http.Handler = http.HandlerFunc(h.replay)
+	if *record {
+		token := os.Getenv("CF_API_TOKEN")
+		if token == "" {
+			t.Fatalf("cftest: -cf.record requires CF_API_TOKEN to be set")
+		}
+		h.cassette = newCassette()
+		handler = h.recordHandler(upstreamBaseURL, token)
+	}
+
+	h.server = httptest.NewServer(handler)
+	t.Cleanup(h.close)
+
+	return h
+}
+
+// Server is the httptest.Server backing the harness.
+func (h *Harness) Server() *httptest.Server {
+	return h.server
+}
+
+func (h *Harness) close() {
+	h.server.Close()
+	if *record {
+		if err := h.cassette.save(h.path); err != nil {
+			h.t.Errorf("cftest: %v", err)
+		}
+	}
+}
+
+func (h *Harness) replay(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	key := requestKey(r.Method, r.URL.Path, r.URL.RawQuery, body)
+
+	h.mu.Lock()
+	interactions := h.cassette.Interactions[key]
+	idx := h.cursor[key]
+	if idx < len(interactions) {
+		h.cursor[key] = idx + 1
+	}
+	h.mu.Unlock()
+
+	if idx >= len(interactions) {
+		msg := fmt.Sprintf("cftest: no recorded interaction #%d for %s; rerun with -cf.record", idx+1, key)
+		h.t.Errorf("%s", msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	interaction := interactions[idx]
+	for name, values := range interaction.Header {
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+	w.WriteHeader(interaction.StatusCode)
+	_, _ = w.Write([]byte(interaction.Body))
+}
+
+func (h *Harness) recordHandler(upstreamBaseURL, token string) http.HandlerFunc {
+	upstream := &http.Client{}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		upstreamReq, err := http.NewRequestWithContext(r.Context(), r.Method, upstreamBaseURL+r.URL.Path, bytes.NewReader(body))
+		if err != nil {
+			msg := fmt.Sprintf("cftest: building upstream request: %v", err)
+			h.t.Errorf("%s", msg)
+			http.Error(w, msg, http.StatusInternalServerError)
+			return
+		}
+		upstreamReq.URL.RawQuery = r.URL.RawQuery
+		upstreamReq.Header = r.Header.Clone()
+		upstreamReq.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := upstream.Do(upstreamReq)
+		if err != nil {
+			msg := fmt.Sprintf("cftest: calling upstream API: %v", err)
+			h.t.Errorf("%s", msg)
+			http.Error(w, msg, http.StatusInternalServerError)
+			return
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			msg := fmt.Sprintf("cftest: reading upstream response: %v", err)
+			h.t.Errorf("%s", msg)
+			http.Error(w, msg, http.StatusInternalServerError)
+			return
+		}
+
+		key := requestKey(r.Method, r.URL.Path, r.URL.RawQuery, body)
+		h.mu.Lock()
+		h.cassette.Interactions[key] = append(h.cassette.Interactions[key], Interaction{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Query:         r.URL.RawQuery,
+			RequestHeader: redactRequestHeaders(r.Header.Clone()),
+			StatusCode:    resp.StatusCode,
+			Header:        redactResponseHeaders(resp.Header.Clone()),
+			Body:          string(redactResponseBody(respBody)),
+		})
+		h.mu.Unlock()
+
+		for name, values := range resp.Header {
+			for _, v := range values {
+				w.Header().Add(name, v)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(respBody)
+	}
+}
@@ -0,0 +1,81 @@
+package cftest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFixture(t *testing.T, name string, c *Cassette) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".json")
+	if err := c.save(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(path) })
+}
+
+func TestHarness_ReplaysPaginationSequenceInOrder(t *testing.T) {
+	key := requestKey(http.MethodGet, "/zones", "", nil)
+	c := newCassette()
+	c.Interactions[key] = []Interaction{
+		{Method: http.MethodGet, Path: "/zones", StatusCode: http.StatusOK, Body: `{"page":1}`},
+		{Method: http.MethodGet, Path: "/zones", StatusCode: http.StatusOK, Body: `{"page":2}`},
+	}
+	writeFixture(t, "pagination", c)
+
+	h := New(t, "pagination", "https://api.cloudflare.com/client/v4")
+
+	first := get(t, h.Server(), "/zones")
+	second := get(t, h.Server(), "/zones")
+
+	assert.JSONEq(t, `{"page":1}`, first)
+	assert.JSONEq(t, `{"page":2}`, second)
+}
+
+func TestHarness_MissingInteractionReturns500InsteadOfFailingTestGoroutine(t *testing.T) {
+	c := newCassette()
+	writeFixture(t, "missing-interaction", c)
+
+	spy := &testing.T{}
+	h := New(spy, "missing-interaction", "https://api.cloudflare.com/client/v4")
+
+	resp, err := http.Get(h.Server().URL + "/zones") //nolint
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "no recorded interaction")
+}
+
+func TestRedactResponseBody(t *testing.T) {
+	redacted := redactResponseBody([]byte(`{"token":"abc123","zone_id":"z1"}`))
+	assert.JSONEq(t, `{"token":"REDACTED","zone_id":"z1"}`, string(redacted))
+}
+
+func TestRequestKey_IgnoresQueryParamOrder(t *testing.T) {
+	a := requestKey(http.MethodGet, "/zones", "page=2&per_page=20", nil)
+	b := requestKey(http.MethodGet, "/zones", "per_page=20&page=2", nil)
+	assert.Equal(t, a, b)
+}
+
+func get(t *testing.T, server *httptest.Server, path string) string {
+	t.Helper()
+	resp, err := http.Get(server.URL + path) //nolint
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,65 @@
+package cftest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// redactedRequestHeaders have their values replaced with "REDACTED" in a
+// recorded interaction's request before it's written to the cassette.
+var redactedRequestHeaders = []string{
+	"X-Auth-Key",
+	"X-Auth-Email",
+	"Authorization",
+	"X-Auth-User-Service-Key",
+}
+
+func redactRequestHeaders(h http.Header) http.Header {
+	for _, name := range redactedRequestHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, "REDACTED")
+		}
+	}
+	return h
+}
+
+func redactResponseHeaders(h http.Header) http.Header {
+	h.Del("Set-Cookie")
+	return h
+}
+
+// redactResponseBody replaces the value of any JSON object field whose name
+// contains "token" (case-insensitively) with "REDACTED". Bodies that
+// aren't valid JSON are returned unchanged.
+func redactResponseBody(body []byte) []byte {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body
+	}
+
+	redactTokenFields(payload)
+
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactTokenFields(v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if strings.Contains(strings.ToLower(k), "token") {
+				val[k] = "REDACTED"
+				continue
+			}
+			redactTokenFields(child)
+		}
+	case []interface{}:
+		for _, child := range val {
+			redactTokenFields(child)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Package cloudflare implements the Cloudflare v4 API.
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// APIVersion is the API version.
+	APIVersion = "v4"
+
+	// defaultBaseURL is the base URL used for all API calls unless overridden.
+	defaultBaseURL = "https://api.cloudflare.com/client/" + APIVersion
+
+	// defaultUserAgent is sent on every request unless overridden.
+	defaultUserAgent = "cloudflare-go"
+
+	// defaultRPS is the default rate limit, in requests per second, applied
+	// to clients that don't call UsingRateLimit.
+	defaultRPS = 4
+)
+
+// AuthType is a flag used to indicate whether the legacy API key/email,
+// user service key, or API token should be used for authentication.
+type AuthType int
+
+// AuthType values.
+const (
+	AuthKeyEmail AuthType = 1 << iota
+	AuthUserService
+	AuthToken
+)
+
+// API holds the configuration for the current API client. A client should
+// not be modified concurrently.
+type API struct {
+	APIKey            string
+	APIEmail          string
+	APIUserServiceKey string
+	APIToken          string
+	UserAgent         string
+	BaseURL           string
+
+	httpClient  *http.Client
+	authType    AuthType
+	rateLimiter *rate.Limiter
+	retryPolicy RetryPolicy
+
+	inFlightSem        chan struct{}
+	longRunningMatcher LongRunningMatcher
+	inFlightCount      int64
+	peakInFlight       int64
+
+	credentialProvider CredentialProvider
+	middlewares        []Middleware
+
+	maxResponseBodyBytes int64
+}
+
+// RetryPolicy controls how the client retries failed requests.
+type RetryPolicy struct {
+	MaxRetries    int
+	MinRetryDelay time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Option is a functional option for configuring the API client.
+type Option func(*API) error
+
+// New creates a new Cloudflare v4 API client using API key and email.
+func New(key, email string, opts ...Option) (*API, error) {
+	if key == "" || email == "" {
+		return nil, fmt.Errorf("cloudflare: key and email must not be empty")
+	}
+
+	api := &API{
+		APIKey:    key,
+		APIEmail:  email,
+		BaseURL:   defaultBaseURL,
+		UserAgent: defaultUserAgent,
+		authType:  AuthKeyEmail,
+		retryPolicy: RetryPolicy{
+			MaxRetries:    3,
+			MinRetryDelay: 1 * time.Second,
+			MaxRetryDelay: 30 * time.Second,
+		},
+	}
+
+	api.credentialProvider = legacyCredentialProvider{api}
+
+	if err := api.parseOptions(opts...); err != nil {
+		return nil, fmt.Errorf("cloudflare: options parsing failed: %w", err)
+	}
+
+	if api.httpClient == nil {
+		api.httpClient = http.DefaultClient
+	}
+
+	if api.rateLimiter == nil {
+		api.rateLimiter = rate.NewLimiter(rate.Limit(defaultRPS), 1)
+	}
+
+	if api.maxResponseBodyBytes == 0 {
+		api.maxResponseBodyBytes = defaultTransportLimits.MaxResponseBodyBytes
+	}
+
+	return api, nil
+}
+
+// NewWithAPIToken creates a new Cloudflare v4 API client using an API token.
+func NewWithAPIToken(token string, opts ...Option) (*API, error) {
+	if token == "" {
+		return nil, fmt.Errorf("cloudflare: token must not be empty")
+	}
+
+	api := &API{
+		APIToken:  token,
+		BaseURL:   defaultBaseURL,
+		UserAgent: defaultUserAgent,
+		authType:  AuthToken,
+		retryPolicy: RetryPolicy{
+			MaxRetries:    3,
+			MinRetryDelay: 1 * time.Second,
+			MaxRetryDelay: 30 * time.Second,
+		},
+	}
+
+	api.credentialProvider = legacyCredentialProvider{api}
+
+	if err := api.parseOptions(opts...); err != nil {
+		return nil, fmt.Errorf("cloudflare: options parsing failed: %w", err)
+	}
+
+	if api.httpClient == nil {
+		api.httpClient = http.DefaultClient
+	}
+
+	if api.rateLimiter == nil {
+		api.rateLimiter = rate.NewLimiter(rate.Limit(defaultRPS), 1)
+	}
+
+	if api.maxResponseBodyBytes == 0 {
+		api.maxResponseBodyBytes = defaultTransportLimits.MaxResponseBodyBytes
+	}
+
+	return api, nil
+}
+
+// NewWithUserServiceKey creates a new Cloudflare v4 API client using a user
+// service key.
+func NewWithUserServiceKey(key string, opts ...Option) (*API, error) {
+	if key == "" {
+		return nil, fmt.Errorf("cloudflare: key must not be empty")
+	}
+
+	api := &API{
+		APIUserServiceKey: key,
+		BaseURL:           defaultBaseURL,
+		UserAgent:         defaultUserAgent,
+		authType:          AuthUserService,
+		retryPolicy: RetryPolicy{
+			MaxRetries:    3,
+			MinRetryDelay: 1 * time.Second,
+			MaxRetryDelay: 30 * time.Second,
+		},
+	}
+
+	api.credentialProvider = legacyCredentialProvider{api}
+
+	if err := api.parseOptions(opts...); err != nil {
+		return nil, fmt.Errorf("cloudflare: options parsing failed: %w", err)
+	}
+
+	if api.httpClient == nil {
+		api.httpClient = http.DefaultClient
+	}
+
+	if api.rateLimiter == nil {
+		api.rateLimiter = rate.NewLimiter(rate.Limit(defaultRPS), 1)
+	}
+
+	if api.maxResponseBodyBytes == 0 {
+		api.maxResponseBodyBytes = defaultTransportLimits.MaxResponseBodyBytes
+	}
+
+	return api, nil
+}
+
+// SetAuthType sets the authentication method used by the client: legacy API
+// key/email, user service key, or API token.
+func (api *API) SetAuthType(authType AuthType) {
+	api.authType = authType
+}
+
+func (api *API) parseOptions(opts ...Option) error {
+	for _, opt := range opts {
+		if err := opt(api); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeRequestContext makes a request to the given endpoint and returns the
+// raw response body, honoring the rate limiter and retry policy and
+// returning as soon as the supplied context is done.
+func (api *API) makeRequestContext(ctx context.Context, method, uri string, params interface{}) ([]byte, error) {
+	var body []byte
+	if params != nil {
+		var err error
+		body, err = json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("cloudflare: error marshalling params to JSON: %w", err)
+		}
+	}
+
+	release, err := api.acquireInFlight(ctx, method, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rt := api.roundTripper()
+
+	var resp *http.Response
+	var respErr error
+	for retries := 0; ; retries++ {
+		if err := api.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		// A fresh reader is required on every attempt: once an earlier
+		// attempt has read from it (even partially, e.g. before the
+		// connection was reset), re-sending the same reader would retry
+		// with an empty or truncated body.
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, api.BaseURL+uri, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("cloudflare: error creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", api.UserAgent)
+
+		resp, respErr = rt(req)
+		if respErr == nil && !isRetryableStatus(resp.StatusCode) {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if retries >= api.retryPolicy.MaxRetries {
+			if respErr != nil {
+				return nil, respErr
+			}
+			break
+		}
+		if respErr == nil {
+			resp.Body.Close()
+		}
+
+		delay := api.retryPolicy.MinRetryDelay * time.Duration(retries+1)
+		if delay > api.retryPolicy.MaxRetryDelay {
+			delay = api.retryPolicy.MaxRetryDelay
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	defer resp.Body.Close()
+
+	limit := responseBodyLimitFromContext(ctx, api.maxResponseBodyBytes)
+	respBody, err := readLimitedBody(resp.Body, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAPIResponse(resp.StatusCode, respBody); err != nil {
+		return nil, err
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,83 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeRequestContext_RetriesResendFullBody(t *testing.T) {
+	setup(UsingRetryPolicy(1, 0, 0))
+	defer teardown()
+
+	var attempt int
+	var gotBody []byte
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		attempt++
+		if attempt == 1 {
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				t.Fatal("test server does not support hijacking")
+			}
+			conn, _, err := hj.Hijack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotBody = body
+		w.Write([]byte(`{"success":true}`)) //nolint
+	})
+
+	_, err := client.makeRequestContext(context.Background(), http.MethodPost, "/zones", map[string]string{"name": "example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempt)
+	assert.JSONEq(t, `{"name":"example.com"}`, string(gotBody))
+}
+
+func TestMakeRequestContext_RetriesOnRateLimitStatus(t *testing.T) {
+	setup(UsingRetryPolicy(1, 0, 0))
+	defer teardown()
+
+	var attempt int
+	mux.HandleFunc("/zones", func(w http.ResponseWriter, r *http.Request) {
+		attempt++
+		if attempt == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"success":false,"errors":[{"code":10000,"message":"rate limited"}]}`)) //nolint
+			return
+		}
+		w.Write([]byte(`{"success":true}`)) //nolint
+	})
+
+	_, err := client.makeRequestContext(context.Background(), http.MethodGet, "/zones", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempt)
+}
+
+func TestMakeRequestContext_SurfacesFailedEnvelope(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"success":false,"errors":[{"code":9109,"message":"Invalid access token"}]}`)) //nolint
+	})
+
+	_, err := client.UserDetails(context.Background())
+	assert.Error(t, err)
+
+	var apiErr *APIRequestError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, []ResponseInfo{{Code: 9109, Message: "Invalid access token"}}, apiErr.Errors)
+}
@@ -2,6 +2,7 @@ package cloudflare
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -22,9 +23,10 @@ var (
 )
 
 func setup(opts ...Option) {
-	// test server
+	// test server, hardened against slow-header and oversized-response
+	// abuse the same way we ask callers to harden their own origins
 	mux = http.NewServeMux()
-	server = httptest.NewServer(mux)
+	server = NewHardenedServer(defaultTransportLimits, mux)
 
 	// disable rate limits and retries in testing - prepended so any provided value overrides this
 	opts = append([]Option{UsingRateLimit(100000), UsingRetryPolicy(0, 0, 0)}, opts...)
@@ -80,6 +82,41 @@ func TestClient_Headers(t *testing.T) {
 	client.UserDetails(context.Background()) //nolint
 	teardown()
 
+	// it should run user middlewares before the built-in auth middleware is
+	// applied, with the auth middleware always innermost
+	setup()
+	var order []string
+	client.Use(func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "first:before")
+			order = append(order, "first:auth-header="+req.Header.Get("X-Auth-Key"))
+			resp, err := next(req)
+			order = append(order, "first:after")
+			return resp, err
+		}
+	}, func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "second:before")
+			order = append(order, "second:auth-header="+req.Header.Get("X-Auth-Key"))
+			resp, err := next(req)
+			order = append(order, "second:after")
+			return resp, err
+		}
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "deadbeef", r.Header.Get("X-Auth-Key"))
+	})
+	client.UserDetails(context.Background()) //nolint
+	assert.Equal(t, []string{
+		"first:before",
+		"first:auth-header=",
+		"second:before",
+		"second:auth-header=",
+		"second:after",
+		"first:after",
+	}, order, "user middlewares should see the request before auth is applied, and the built-in auth middleware should always run last")
+	teardown()
+
 	// it should set X-Auth-User-Service-Key and omit X-Auth-Email and X-Auth-Key when using NewWithUserServiceKey
 	setup()
 	client, err := NewWithUserServiceKey("userservicekey")
@@ -113,12 +150,6 @@ func TestClient_Headers(t *testing.T) {
 	teardown()
 }
 
-type RoundTripperFunc func(*http.Request) (*http.Response, error)
-
-func (t RoundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
-	return t(request)
-}
-
 func TestContextTimeout(t *testing.T) {
 	setup()
 	defer teardown()
@@ -167,6 +198,33 @@ func TestCheckResultInfo(t *testing.T) {
 	} {
 		t.Run(c.TestName, func(t *testing.T) {
 			assert.Equal(t, c.Verdict, checkResultInfo(c.PerPage, c.Page, c.Count, &c.ResultInfo))
+
+			// A Paginator always fetches page 1 first, so cases framed as a
+			// first-page fetch (c.Page == 1) double as a check that the
+			// Paginator agrees with checkResultInfo: an inconsistent page
+			// halts iteration with ErrInconsistentPagination, a consistent
+			// one doesn't.
+			if c.Page != 1 {
+				return
+			}
+
+			info := c.ResultInfo
+			count := c.Count
+			fetch := func(ctx context.Context, page, perPage int) ([]int, ResultInfo, error) {
+				return make([]int, count), info, nil
+			}
+
+			p := NewPaginator[int](context.Background(), fetch, WithPerPage(c.PerPage))
+			defer p.Close()
+
+			_, err := p.Next(context.Background())
+			var paginationErr *ErrInconsistentPagination
+			if c.Verdict {
+				assert.False(t, errors.As(err, &paginationErr))
+			} else {
+				assert.ErrorAs(t, err, &paginationErr)
+				assert.Equal(t, c.PerPage, paginationErr.PerPage)
+			}
 		})
 	}
 }
@@ -0,0 +1,94 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CredentialProvider applies authentication to an outgoing request. It is
+// invoked on every call made through makeRequestContext, so implementations
+// that mint short-lived credentials, such as AccessTokenProvider, can
+// refresh them transparently instead of requiring the caller to rebuild the
+// client.
+type CredentialProvider interface {
+	ApplyAuth(ctx context.Context, req *http.Request) error
+}
+
+// AuthError wraps a failure to authenticate a request, identifying which
+// CredentialProvider produced it.
+type AuthError struct {
+	Provider string
+	Err      error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("cloudflare: auth provider %q: %v", e.Provider, e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// UsingCredentialProvider overrides the client's authentication with a
+// custom CredentialProvider. It takes precedence over the legacy API
+// key/email, user service key, and API token modes selected via New,
+// NewWithAPIToken, NewWithUserServiceKey, and SetAuthType.
+func UsingCredentialProvider(p CredentialProvider) Option {
+	return func(api *API) error {
+		api.credentialProvider = p
+		return nil
+	}
+}
+
+// legacyCredentialProvider reproduces the original header selection based
+// on api.authType, so SetAuthType and the credential fields on API keep
+// working for callers that haven't opted into a custom CredentialProvider.
+type legacyCredentialProvider struct {
+	api *API
+}
+
+func (l legacyCredentialProvider) ApplyAuth(_ context.Context, req *http.Request) error {
+	switch l.api.authType {
+	case AuthToken:
+		if l.api.APIToken == "" {
+			return &AuthError{Provider: "api-token", Err: fmt.Errorf("missing API token")}
+		}
+		req.Header.Set("Authorization", "Bearer "+l.api.APIToken)
+	case AuthUserService:
+		if l.api.APIUserServiceKey == "" {
+			return &AuthError{Provider: "user-service-key", Err: fmt.Errorf("missing user service key")}
+		}
+		req.Header.Set("X-Auth-User-Service-Key", l.api.APIUserServiceKey)
+	default:
+		if l.api.APIKey == "" || l.api.APIEmail == "" {
+			return &AuthError{Provider: "key-email", Err: fmt.Errorf("missing API key or email")}
+		}
+		req.Header.Set("X-Auth-Key", l.api.APIKey)
+		req.Header.Set("X-Auth-Email", l.api.APIEmail)
+	}
+	return nil
+}
+
+// ChainProvider tries each CredentialProvider in order and applies the
+// first one that succeeds, so callers can fall back from, say, an
+// AccessTokenProvider to a static API token.
+type ChainProvider struct {
+	Providers []CredentialProvider
+}
+
+// ApplyAuth implements CredentialProvider.
+func (c ChainProvider) ApplyAuth(ctx context.Context, req *http.Request) error {
+	var lastErr error
+	for _, p := range c.Providers {
+		if err := p.ApplyAuth(ctx, req); err == nil {
+			return nil
+		} else {
+			lastErr = err
+		}
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no credential providers configured")
+	}
+	return &AuthError{Provider: "chain", Err: lastErr}
+}
@@ -0,0 +1,110 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingStore hands out the next configured token on each call, so tests
+// can observe rotation and reuse across requests.
+type countingStore struct {
+	calls  int
+	tokens []AccessToken
+	err    error
+}
+
+func (s *countingStore) Token(ctx context.Context) (AccessToken, error) {
+	if s.err != nil {
+		return AccessToken{}, s.err
+	}
+	tok := s.tokens[s.calls]
+	s.calls++
+	return tok, nil
+}
+
+func TestAccessTokenProvider_ReusesUnexpiredToken(t *testing.T) {
+	store := &countingStore{tokens: []AccessToken{
+		{Value: "first", ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	provider := NewAccessTokenProvider(store)
+
+	setup(UsingCredentialProvider(provider))
+	defer teardown()
+
+	var seen []string
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Cf-Access-Token"))
+	})
+
+	client.UserDetails(context.Background()) //nolint
+	client.UserDetails(context.Background()) //nolint
+
+	assert.Equal(t, []string{"first", "first"}, seen)
+	assert.Equal(t, 1, store.calls, "the store should only be asked to mint once while the token is valid")
+}
+
+func TestAccessTokenProvider_RotatesOnExpiry(t *testing.T) {
+	store := &countingStore{tokens: []AccessToken{
+		{Value: "first", ExpiresAt: time.Now().Add(10 * time.Millisecond)},
+		{Value: "second", ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	provider := NewAccessTokenProvider(store)
+
+	setup(UsingCredentialProvider(provider))
+	defer teardown()
+
+	var seen []string
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Cf-Access-Token"))
+	})
+
+	client.UserDetails(context.Background()) //nolint
+	time.Sleep(20 * time.Millisecond)
+	client.UserDetails(context.Background()) //nolint
+
+	assert.Equal(t, []string{"first", "second"}, seen)
+	assert.Equal(t, 2, store.calls)
+}
+
+func TestAccessTokenProvider_Error(t *testing.T) {
+	store := &countingStore{err: errors.New("token endpoint unreachable")}
+	provider := NewAccessTokenProvider(store)
+
+	setup(UsingCredentialProvider(provider))
+	defer teardown()
+
+	_, err := client.UserDetails(context.Background())
+	var authErr *AuthError
+	assert.ErrorAs(t, err, &authErr)
+	assert.Equal(t, "access-token", authErr.Provider)
+}
+
+func TestChainProvider(t *testing.T) {
+	failing := NewAccessTokenProvider(&countingStore{err: errors.New("no access token")})
+	fallback := legacyCredentialProvider{api: &API{authType: AuthToken, APIToken: "chained-token"}}
+
+	setup(UsingCredentialProvider(ChainProvider{Providers: []CredentialProvider{failing, fallback}}))
+	defer teardown()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer chained-token", r.Header.Get("Authorization"))
+	})
+	client.UserDetails(context.Background()) //nolint
+}
+
+func TestChainProvider_AllFail(t *testing.T) {
+	first := NewAccessTokenProvider(&countingStore{err: errors.New("first failed")})
+	second := NewAccessTokenProvider(&countingStore{err: errors.New("second failed")})
+
+	chain := ChainProvider{Providers: []CredentialProvider{first, second}}
+	err := chain.ApplyAuth(context.Background(), &http.Request{Header: make(http.Header)})
+
+	var authErr *AuthError
+	assert.ErrorAs(t, err, &authErr)
+	assert.Equal(t, "chain", authErr.Provider)
+}
@@ -0,0 +1,71 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponseInfo holds a single error or message returned by the Cloudflare
+// API inside a response envelope.
+type ResponseInfo struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Response is the base response envelope shared by all Cloudflare API
+// responses, carrying the result payload in whichever typed wrapper embeds
+// it.
+type Response struct {
+	Success  bool           `json:"success"`
+	Errors   []ResponseInfo `json:"errors"`
+	Messages []ResponseInfo `json:"messages"`
+}
+
+// Error implements the error interface for a single ResponseInfo so it can
+// be returned or wrapped directly.
+func (e ResponseInfo) Error() string {
+	return fmt.Sprintf("cloudflare: %d: %s", e.Code, e.Message)
+}
+
+// APIRequestError is returned by makeRequestContext when the Cloudflare API
+// responds with a non-2xx status, or with a 2xx status whose response
+// envelope reports success: false with at least one error.
+type APIRequestError struct {
+	StatusCode int
+	Errors     []ResponseInfo
+}
+
+func (e *APIRequestError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("cloudflare: request failed with status %d", e.StatusCode)
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, info := range e.Errors {
+		msgs[i] = info.Error()
+	}
+	return fmt.Sprintf("cloudflare: request failed with status %d: %s", e.StatusCode, strings.Join(msgs, "; "))
+}
+
+// checkAPIResponse returns an *APIRequestError if statusCode is not a 2xx,
+// or if body decodes as a Response envelope with Success: false and a
+// non-empty Errors list. A body that isn't a Response envelope (or is
+// empty), or one with Success: false but no errors, is not itself an error,
+// so endpoints that don't echo the standard envelope aren't penalized.
+func checkAPIResponse(statusCode int, body []byte) error {
+	var envelope Response
+	envelopeErr := json.Unmarshal(body, &envelope)
+
+	if statusCode < 200 || statusCode >= 300 || (envelopeErr == nil && !envelope.Success && len(envelope.Errors) > 0) {
+		return &APIRequestError{StatusCode: statusCode, Errors: envelope.Errors}
+	}
+
+	return nil
+}
+
+// isRetryableStatus reports whether statusCode represents a transient
+// failure worth retrying under the client's retry policy: 429 (rate
+// limited) and any 5xx server error.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == 429 || statusCode >= 500
+}
@@ -0,0 +1,91 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// LongRunningMatcher reports whether a request to the given method and path
+// should bypass the in-flight concurrency limiter configured via
+// UsingMaxInFlight, analogous to the long-running request matcher used by
+// Kubernetes' generic API server to exempt things like log tails and
+// watches from its MaxRequestsInFlight limit.
+type LongRunningMatcher func(method, path string) bool
+
+// ErrTooManyInFlight is returned by makeRequestContext when the client's
+// in-flight limiter is saturated and the request's context is done before a
+// slot frees up.
+var ErrTooManyInFlight = errors.New("cloudflare: too many requests in flight")
+
+// UsingMaxInFlight bounds the number of non-long-running requests the
+// client will have outstanding at once, protecting callers from
+// thundering-herd fan-outs across zones without involving the API rate
+// limiter. Requests made while the limit is saturated block until a slot
+// frees up or their context is done, whichever comes first. A non-positive
+// n disables the limiter, which is the default.
+func UsingMaxInFlight(n int) Option {
+	return func(api *API) error {
+		if n <= 0 {
+			api.inFlightSem = nil
+			return nil
+		}
+		api.inFlightSem = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// UsingLongRunningMatcher sets the predicate used to exempt requests, such
+// as log tails, streaming analytics pulls, or exports, from the in-flight
+// limiter configured via UsingMaxInFlight.
+func UsingLongRunningMatcher(matcher LongRunningMatcher) Option {
+	return func(api *API) error {
+		api.longRunningMatcher = matcher
+		return nil
+	}
+}
+
+// InFlight returns the number of requests currently held by the in-flight
+// limiter.
+func (api *API) InFlight() int {
+	return int(atomic.LoadInt64(&api.inFlightCount))
+}
+
+// PeakInFlight returns the highest number of requests the in-flight limiter
+// has held concurrently since the client was created.
+func (api *API) PeakInFlight() int {
+	return int(atomic.LoadInt64(&api.peakInFlight))
+}
+
+// acquireInFlight blocks until a concurrency slot is available for method
+// and path, unless the client has no limiter configured or the request is
+// classified as long-running by the configured LongRunningMatcher. The
+// returned release func must be called exactly once, when the request
+// completes.
+func (api *API) acquireInFlight(ctx context.Context, method, path string) (func(), error) {
+	if api.inFlightSem == nil {
+		return func() {}, nil
+	}
+	if api.longRunningMatcher != nil && api.longRunningMatcher(method, path) {
+		return func() {}, nil
+	}
+
+	select {
+	case api.inFlightSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ErrTooManyInFlight
+	}
+
+	current := atomic.AddInt64(&api.inFlightCount, 1)
+	for {
+		peak := atomic.LoadInt64(&api.peakInFlight)
+		if current <= peak || atomic.CompareAndSwapInt64(&api.peakInFlight, peak, current) {
+			break
+		}
+	}
+
+	return func() {
+		atomic.AddInt64(&api.inFlightCount, -1)
+		<-api.inFlightSem
+	}, nil
+}
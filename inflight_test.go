@@ -0,0 +1,74 @@
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	setup(UsingMaxInFlight(1))
+	defer teardown()
+
+	release := make(chan struct{})
+	mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = client.makeRequestContext(context.Background(), http.MethodGet, "/zones/1", nil) //nolint
+	}()
+
+	// give the first request a chance to acquire the only slot
+	assert.Eventually(t, func() bool { return client.InFlight() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.makeRequestContext(ctx, http.MethodGet, "/zones/2", nil)
+	assert.ErrorIs(t, err, ErrTooManyInFlight)
+	assert.Equal(t, 1, client.PeakInFlight())
+
+	close(release)
+	wg.Wait()
+}
+
+func TestMaxInFlightLongRunningBypass(t *testing.T) {
+	setup(
+		UsingMaxInFlight(1),
+		UsingLongRunningMatcher(func(method, path string) bool {
+			return strings.HasSuffix(path, "/logs/tail")
+		}),
+	)
+	defer teardown()
+
+	release := make(chan struct{})
+	mux.HandleFunc("/zones/1", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	mux.HandleFunc("/zones/1/logs/tail", func(w http.ResponseWriter, r *http.Request) {})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = client.makeRequestContext(context.Background(), http.MethodGet, "/zones/1", nil) //nolint
+	}()
+
+	assert.Eventually(t, func() bool { return client.InFlight() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := client.makeRequestContext(ctx, http.MethodGet, "/zones/1/logs/tail", nil)
+	assert.NoError(t, err)
+
+	close(release)
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package cloudflare
+
+import "net/http"
+
+// RoundTripperFunc adapts an ordinary function into an http.RoundTripper,
+// analogous to http.HandlerFunc.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a RoundTripperFunc with additional behavior, forming a
+// chain that every request made through makeRequestContext passes through.
+type Middleware func(next RoundTripperFunc) RoundTripperFunc
+
+// Use appends middleware to the client's round-trip chain. Middlewares run
+// in the order they were added, each wrapping everything registered after
+// it. The built-in auth middleware always runs last, immediately before
+// the request is sent, so a request passed down through user middlewares
+// never carries the applied credentials yet, and no user middleware can
+// strip them before the request is sent. Auth sets its headers on that same
+// request, so code a user middleware runs after next returns sees them.
+func (api *API) Use(mw ...Middleware) {
+	api.middlewares = append(api.middlewares, mw...)
+}
+
+// roundTripper builds the full middleware chain: every user middleware
+// registered via Use, wrapped around the built-in auth middleware, wrapped
+// around the underlying *http.Client.
+func (api *API) roundTripper() RoundTripperFunc {
+	chain := api.authMiddleware()(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return api.httpClient.Do(req)
+	}))
+
+	for i := len(api.middlewares) - 1; i >= 0; i-- {
+		chain = api.middlewares[i](chain)
+	}
+
+	return chain
+}
+
+func (api *API) authMiddleware() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := api.credentialProvider.ApplyAuth(req.Context(), req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
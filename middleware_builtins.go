@@ -0,0 +1,123 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestHeaders returns a Middleware that sets the given headers on every
+// outgoing request, overriding any header of the same name already present,
+// analogous to the CustomRequestHeaders option in traefik's headers
+// middleware.
+func RequestHeaders(headers http.Header) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			for key, values := range headers {
+				req.Header.Del(key)
+				for _, v := range values {
+					req.Header.Add(key, v)
+				}
+			}
+			return next(req)
+		}
+	}
+}
+
+// ResponseHeaders returns a Middleware that sets the given headers on every
+// response returned to the caller, mirroring CustomResponseHeaders.
+func ResponseHeaders(headers http.Header) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err != nil {
+				return resp, err
+			}
+			for key, values := range headers {
+				resp.Header.Del(key)
+				for _, v := range values {
+					resp.Header.Add(key, v)
+				}
+			}
+			return resp, nil
+		}
+	}
+}
+
+// RequestID returns a Middleware that stamps every outgoing request with a
+// random X-Request-Id header, unless the caller has already set one, so
+// client-side logs can be correlated with Cloudflare-side request traces.
+func RequestID() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Request-Id") == "" {
+				id, err := newRequestID()
+				if err != nil {
+					return nil, fmt.Errorf("cloudflare: generating request id: %w", err)
+				}
+				req.Header.Set("X-Request-Id", id)
+			}
+			return next(req)
+		}
+	}
+}
+
+func newRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Logger is the minimal logging interface accepted by RequestLogging,
+// satisfied by the standard library's *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// RequestLogging returns a Middleware that logs the method, path, and
+// outcome of every request made through the client.
+func RequestLogging(logger Logger) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			if err != nil {
+				logger.Printf("cloudflare: %s %s failed after %s: %v", req.Method, req.URL.Path, time.Since(start), err)
+				return resp, err
+			}
+			logger.Printf("cloudflare: %s %s -> %s in %s", req.Method, req.URL.Path, resp.Status, time.Since(start))
+			return resp, nil
+		}
+	}
+}
+
+// ForwardHTTPHeaders extracts the headers that should be copied from an
+// inbound request onto outgoing Cloudflare API calls, the way Grafana's
+// plugin SDK forwards end-user headers through proxied data source
+// requests.
+type ForwardHTTPHeaders interface {
+	GetHTTPHeaders(ctx context.Context) http.Header
+}
+
+// ForwardHeaders returns a Middleware that copies the named headers from the
+// context carried by each request (as supplied by source) onto the
+// outgoing request, so a server embedding this client can pass through
+// end-user headers like Cf-Ray or trace headers.
+func ForwardHeaders(source ForwardHTTPHeaders, names ...string) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			inbound := source.GetHTTPHeaders(req.Context())
+			for _, name := range names {
+				if v := inbound.Get(name); v != "" {
+					req.Header.Set(name, v)
+				}
+			}
+			return next(req)
+		}
+	}
+}
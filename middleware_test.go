@@ -0,0 +1,60 @@
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_RequestHeadersOverridesDefaultContentType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/xhtml+xml")
+	client.Use(RequestHeaders(headers))
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/xhtml+xml", r.Header.Get("Content-Type"))
+	})
+	client.UserDetails(context.Background()) //nolint
+}
+
+func TestMiddleware_RequestID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Use(RequestID())
+
+	var seen string
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Get("X-Request-Id")
+	})
+	client.UserDetails(context.Background()) //nolint
+
+	assert.NotEmpty(t, seen)
+}
+
+type staticForwardHeaders struct {
+	headers http.Header
+}
+
+func (s staticForwardHeaders) GetHTTPHeaders(ctx context.Context) http.Header {
+	return s.headers
+}
+
+func TestMiddleware_ForwardHeaders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	inbound := make(http.Header)
+	inbound.Set("Cf-Ray", "abc123")
+	client.Use(ForwardHeaders(staticForwardHeaders{headers: inbound}, "Cf-Ray"))
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.Header.Get("Cf-Ray"))
+	})
+	client.UserDetails(context.Background()) //nolint
+}
@@ -0,0 +1,46 @@
+package cloudflare
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// UsingRateLimit applies a non-default rate limit to client API requests.
+// The default rate limit is 4 requests per second, matching the default
+// Cloudflare API rate limit for most endpoints.
+func UsingRateLimit(rps float64) Option {
+	return func(api *API) error {
+		api.rateLimiter = rate.NewLimiter(rate.Limit(rps), 1)
+		return nil
+	}
+}
+
+// UsingRetryPolicy applies a non-default number of retries and minimum/
+// maximum retry delays for requests that fail due to network errors, or
+// that come back with a 429 or 5xx status. Delays grow linearly with the
+// retry count (minRetryDelay * attempt), capped at maxRetryDelay.
+func UsingRetryPolicy(maxRetries int, minRetryDelay, maxRetryDelay time.Duration) Option {
+	return func(api *API) error {
+		api.retryPolicy = RetryPolicy{
+			MaxRetries:    maxRetries,
+			MinRetryDelay: minRetryDelay,
+			MaxRetryDelay: maxRetryDelay,
+		}
+		return nil
+	}
+}
+
+// Headers allows you to set custom HTTP headers when making requests, like
+// when you need to set a X-Auth-User-Service-Key header for some endpoints.
+// These headers supersede the library's default request headers, such as
+// Content-Type, but not the credential headers set by the client's
+// CredentialProvider, which is applied after them. It is a thin wrapper
+// around the built-in RequestHeaders middleware.
+func Headers(headers http.Header) Option {
+	return func(api *API) error {
+		api.Use(RequestHeaders(headers))
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package cloudflare
+
+// ResultInfo contains the pagination information for paginated API
+// responses.
+type ResultInfo struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	TotalPages int    `json:"total_pages"`
+	Count      int    `json:"count"`
+	Total      int    `json:"total_count"`
+	Cursor     string `json:"cursor,omitempty"`
+	Cursors    struct {
+		Before string `json:"before,omitempty"`
+		After  string `json:"after,omitempty"`
+	} `json:"cursors,omitempty"`
+}
+
+// checkResultInfo verifies that the ResultInfo returned alongside a page of
+// results is internally consistent given the perPage/page/count the caller
+// requested, so that pagination code can detect a malformed or truncated
+// response before it loops forever.
+func checkResultInfo(perPage, page, count int, resultInfo *ResultInfo) bool {
+	if resultInfo == nil {
+		return false
+	}
+
+	if resultInfo.PerPage != perPage || resultInfo.Page != page || resultInfo.Count != count {
+		return false
+	}
+
+	if resultInfo.PerPage <= 0 || resultInfo.Page < 1 {
+		return false
+	}
+
+	// No results at all: zero pages and no items.
+	if resultInfo.Total == 0 {
+		return resultInfo.TotalPages == 0 && resultInfo.Count == 0
+	}
+
+	expectedTotalPages := (resultInfo.Total + resultInfo.PerPage - 1) / resultInfo.PerPage
+	if resultInfo.TotalPages != expectedTotalPages {
+		return false
+	}
+
+	if resultInfo.Page > resultInfo.TotalPages {
+		return false
+	}
+
+	if resultInfo.Page < resultInfo.TotalPages {
+		return resultInfo.Count == resultInfo.PerPage
+	}
+
+	return resultInfo.Count == resultInfo.Total-(resultInfo.TotalPages-1)*resultInfo.PerPage
+}
@@ -0,0 +1,193 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// ErrInconsistentPagination is returned by a Paginator when a fetched
+// page's ResultInfo fails the consistency check performed by
+// checkResultInfo, carrying the values involved so callers can log or
+// report the malformed response instead of looping on it forever.
+type ErrInconsistentPagination struct {
+	PerPage    int
+	Page       int
+	Count      int
+	ResultInfo ResultInfo
+}
+
+func (e *ErrInconsistentPagination) Error() string {
+	return fmt.Sprintf(
+		"cloudflare: inconsistent pagination: requested page %d (per_page %d, count %d), got %+v",
+		e.Page, e.PerPage, e.Count, e.ResultInfo,
+	)
+}
+
+// PageFetcher retrieves a single page of items for the given 1-indexed page
+// number and page size, returning the decoded items alongside the
+// ResultInfo envelope the API returned for that page.
+type PageFetcher[T any] func(ctx context.Context, page, perPage int) ([]T, ResultInfo, error)
+
+const defaultPaginatorPerPage = 25
+
+// PaginatorOption configures a Paginator returned by NewPaginator.
+type PaginatorOption func(*paginatorConfig)
+
+type paginatorConfig struct {
+	perPage  int
+	prefetch int
+}
+
+// WithPerPage sets the page size requested from the PageFetcher. It
+// defaults to 25 if not given.
+func WithPerPage(n int) PaginatorOption {
+	return func(c *paginatorConfig) {
+		c.perPage = n
+	}
+}
+
+// WithPrefetch lets the Paginator buffer up to n upcoming pages while the
+// caller is still processing the current one, pipelining network latency
+// with item processing. Pages are fetched one at a time by a single
+// background goroutine, which holds at most n+1 pages ahead of the one the
+// caller is consuming. It defaults to 0; even then the goroutine fetches the
+// first page as soon as the Paginator is created and one page ahead after
+// that, so fetching is not strictly on demand.
+func WithPrefetch(n int) PaginatorOption {
+	return func(c *paginatorConfig) {
+		c.prefetch = n
+	}
+}
+
+type pageResult[T any] struct {
+	items []T
+	info  ResultInfo
+	err   error
+}
+
+// Paginator walks successive pages of a paginated Cloudflare API listing,
+// validating each page's ResultInfo with checkResultInfo before handing its
+// items to the caller.
+type Paginator[T any] struct {
+	perPage int
+	items   []T
+	idx     int
+	info    ResultInfo
+	err     error
+	done    bool
+	pages   <-chan pageResult[T]
+	cancel  context.CancelFunc
+}
+
+// NewPaginator creates a Paginator that calls fetch for successive pages,
+// stopping with an ErrInconsistentPagination as soon as a page's ResultInfo
+// fails validation.
+func NewPaginator[T any](ctx context.Context, fetch PageFetcher[T], opts ...PaginatorOption) *Paginator[T] {
+	cfg := paginatorConfig{perPage: defaultPaginatorPerPage}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	pages := make(chan pageResult[T], cfg.prefetch)
+	p := &Paginator[T]{perPage: cfg.perPage, pages: pages, cancel: cancel}
+
+	go runPaginator(runCtx, fetch, cfg, pages)
+
+	return p
+}
+
+func runPaginator[T any](ctx context.Context, fetch PageFetcher[T], cfg paginatorConfig, out chan<- pageResult[T]) {
+	defer close(out)
+
+	for page := 1; ; page++ {
+		items, info, err := fetch(ctx, page, cfg.perPage)
+		if err != nil {
+			sendPage(ctx, out, pageResult[T]{err: err})
+			return
+		}
+
+		if !checkResultInfo(cfg.perPage, page, len(items), &info) {
+			sendPage(ctx, out, pageResult[T]{err: &ErrInconsistentPagination{
+				PerPage: cfg.perPage, Page: page, Count: len(items), ResultInfo: info,
+			}})
+			return
+		}
+
+		if !sendPage(ctx, out, pageResult[T]{items: items, info: info}) {
+			return
+		}
+
+		if page >= info.TotalPages {
+			return
+		}
+	}
+}
+
+func sendPage[T any](ctx context.Context, out chan<- pageResult[T], p pageResult[T]) bool {
+	select {
+	case out <- p:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Next advances the iterator and returns the next item. It returns io.EOF
+// once every page has been consumed, or the error that aborted iteration
+// (a fetch error, an ErrInconsistentPagination, or ctx.Err()) otherwise.
+func (p *Paginator[T]) Next(ctx context.Context) (T, error) {
+	var zero T
+
+	for p.idx >= len(p.items) {
+		if p.done {
+			if p.err != nil {
+				return zero, p.err
+			}
+			return zero, io.EOF
+		}
+
+		select {
+		case page, ok := <-p.pages:
+			if !ok {
+				p.done = true
+				continue
+			}
+			if page.err != nil {
+				p.err = page.err
+				p.done = true
+				continue
+			}
+			p.items = page.items
+			p.info = page.info
+			p.idx = 0
+		case <-ctx.Done():
+			p.err = ctx.Err()
+			p.done = true
+		}
+	}
+
+	item := p.items[p.idx]
+	p.idx++
+	return item, nil
+}
+
+// Err returns the error, if any, that stopped iteration early. It returns
+// nil if iteration hasn't failed (including if it hasn't finished yet).
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// Page returns the ResultInfo for the page whose items Next is currently
+// returning. Pages prefetched in the background are not reflected until
+// Next reaches them.
+func (p *Paginator[T]) Page() ResultInfo {
+	return p.info
+}
+
+// Close stops any in-flight prefetching. Callers that abandon a Paginator
+// before exhausting it should call Close to release its goroutine.
+func (p *Paginator[T]) Close() {
+	p.cancel()
+}
@@ -0,0 +1,52 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginator_WalksAllPages(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+	fetch := func(ctx context.Context, page, perPage int) ([]int, ResultInfo, error) {
+		items := pages[page-1]
+		total := 0
+		for _, p := range pages {
+			total += len(p)
+		}
+		return items, ResultInfo{Page: page, PerPage: 2, TotalPages: len(pages), Count: len(items), Total: total}, nil
+	}
+
+	p := NewPaginator[int](context.Background(), fetch, WithPerPage(2), WithPrefetch(2))
+	defer p.Close()
+
+	var got []int
+	for {
+		item, err := p.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		got = append(got, item)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	assert.NoError(t, p.Err())
+	assert.Equal(t, 3, p.Page().TotalPages)
+}
+
+func TestPaginator_PropagatesFetchError(t *testing.T) {
+	fetchErr := errors.New("boom")
+	fetch := func(ctx context.Context, page, perPage int) ([]int, ResultInfo, error) {
+		return nil, ResultInfo{}, fetchErr
+	}
+
+	p := NewPaginator[int](context.Background(), fetch)
+	defer p.Close()
+
+	_, err := p.Next(context.Background())
+	assert.ErrorIs(t, err, fetchErr)
+}
@@ -0,0 +1,152 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// TransportLimits bounds the resources a client (or a test harness
+// standing in for the real API) will spend on a single connection,
+// mirroring the long-header/large-response mitigations ipfs-cluster added
+// to its proxy config to protect against a misbehaving or malicious peer
+// sitting in front of the real service.
+type TransportLimits struct {
+	// MaxResponseHeaderBytes caps the size of response headers the client
+	// will read, via (*http.Transport).MaxResponseHeaderBytes.
+	MaxResponseHeaderBytes int64
+
+	// MaxResponseBodyBytes caps the size of a response body the client will
+	// buffer. Requests exceeding it fail with ErrResponseTooLarge. Use
+	// WithResponseBodyLimit to override this per request.
+	MaxResponseBodyBytes int64
+
+	// ReadHeaderTimeout bounds how long a server (such as the embedded
+	// httptest.Server built by NewHardenedServer) will wait to finish
+	// reading a request's headers.
+	ReadHeaderTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds how long the client's *http.Transport
+	// will wait for response headers after writing the request.
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnTimeout bounds how long an idle keep-alive connection is kept
+	// in the client's connection pool.
+	IdleConnTimeout time.Duration
+
+	// MaxIdleConnsPerHost caps the number of idle keep-alive connections
+	// kept per host in the client's connection pool.
+	MaxIdleConnsPerHost int
+}
+
+// defaultTransportLimits are applied by UsingTransportLimits when a caller
+// hasn't filled in every field: a 1 MiB header cap, a 32 MiB body cap, and
+// a 10s header timeout.
+var defaultTransportLimits = TransportLimits{
+	MaxResponseHeaderBytes: 1 << 20,
+	MaxResponseBodyBytes:   32 << 20,
+	ReadHeaderTimeout:      10 * time.Second,
+	ResponseHeaderTimeout:  10 * time.Second,
+	IdleConnTimeout:        90 * time.Second,
+	MaxIdleConnsPerHost:    10,
+}
+
+// ErrResponseTooLarge is returned when a response body exceeds the
+// configured MaxResponseBodyBytes limit.
+var ErrResponseTooLarge = errors.New("cloudflare: response body exceeds configured size limit")
+
+// UsingTransportLimits replaces the client's *http.Transport with a clone
+// of http.DefaultTransport that overrides only the limited fields, and caps
+// the size of response bodies the client will buffer. Cloning rather than
+// building a bare *http.Transport preserves defaults such as
+// Proxy: http.ProxyFromEnvironment, so callers behind a proxy don't lose
+// HTTP_PROXY/HTTPS_PROXY support by opting into this hardening. Fields left
+// at their zero value fall back to defaultTransportLimits.
+func UsingTransportLimits(limits TransportLimits) Option {
+	return func(api *API) error {
+		limits = fillTransportLimitDefaults(limits)
+		api.maxResponseBodyBytes = limits.MaxResponseBodyBytes
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxResponseHeaderBytes = limits.MaxResponseHeaderBytes
+		transport.ResponseHeaderTimeout = limits.ResponseHeaderTimeout
+		transport.IdleConnTimeout = limits.IdleConnTimeout
+		transport.MaxIdleConnsPerHost = limits.MaxIdleConnsPerHost
+
+		api.httpClient = &http.Client{Transport: transport}
+		return nil
+	}
+}
+
+func fillTransportLimitDefaults(limits TransportLimits) TransportLimits {
+	if limits.MaxResponseHeaderBytes == 0 {
+		limits.MaxResponseHeaderBytes = defaultTransportLimits.MaxResponseHeaderBytes
+	}
+	if limits.MaxResponseBodyBytes == 0 {
+		limits.MaxResponseBodyBytes = defaultTransportLimits.MaxResponseBodyBytes
+	}
+	if limits.ReadHeaderTimeout == 0 {
+		limits.ReadHeaderTimeout = defaultTransportLimits.ReadHeaderTimeout
+	}
+	if limits.ResponseHeaderTimeout == 0 {
+		limits.ResponseHeaderTimeout = defaultTransportLimits.ResponseHeaderTimeout
+	}
+	if limits.IdleConnTimeout == 0 {
+		limits.IdleConnTimeout = defaultTransportLimits.IdleConnTimeout
+	}
+	if limits.MaxIdleConnsPerHost == 0 {
+		limits.MaxIdleConnsPerHost = defaultTransportLimits.MaxIdleConnsPerHost
+	}
+	return limits
+}
+
+// NewHardenedServer starts an httptest.Server for handler with
+// limits.ReadHeaderTimeout applied to its underlying http.Server, so tests
+// exercising this SDK's hardening options can also mimic a slow-loris-
+// resistant origin.
+func NewHardenedServer(limits TransportLimits, handler http.Handler) *httptest.Server {
+	limits = fillTransportLimitDefaults(limits)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ReadHeaderTimeout = limits.ReadHeaderTimeout
+	server.Start()
+
+	return server
+}
+
+type responseBodyLimitCtxKey struct{}
+
+// WithResponseBodyLimit overrides the client's configured
+// MaxResponseBodyBytes for requests made with the returned context. A limit
+// of 0 disables the cap entirely, which streaming endpoints such as log
+// tails or exports can use to opt out.
+func WithResponseBodyLimit(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, responseBodyLimitCtxKey{}, limit)
+}
+
+func responseBodyLimitFromContext(ctx context.Context, fallback int64) int64 {
+	if limit, ok := ctx.Value(responseBodyLimitCtxKey{}).(int64); ok {
+		return limit
+	}
+	return fallback
+}
+
+// readLimitedBody reads body, failing with ErrResponseTooLarge if it
+// exceeds limit bytes. A non-positive limit disables the cap.
+func readLimitedBody(body io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrResponseTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,65 @@
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportLimits_ResponseTooLarge(t *testing.T) {
+	setup(UsingTransportLimits(TransportLimits{MaxResponseBodyBytes: 16}))
+	defer teardown()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 17))) //nolint
+	})
+
+	_, err := client.makeRequestContext(context.Background(), http.MethodGet, "/user", nil)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestTransportLimits_WithinLimitSucceeds(t *testing.T) {
+	setup(UsingTransportLimits(TransportLimits{MaxResponseBodyBytes: 16}))
+	defer teardown()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 16))) //nolint
+	})
+
+	body, err := client.makeRequestContext(context.Background(), http.MethodGet, "/user", nil)
+	assert.NoError(t, err)
+	assert.Len(t, body, 16)
+}
+
+func TestTransportLimits_PerRequestOverride(t *testing.T) {
+	setup(UsingTransportLimits(TransportLimits{MaxResponseBodyBytes: 16}))
+	defer teardown()
+
+	mux.HandleFunc("/logs/tail", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 1024))) //nolint
+	})
+
+	ctx := WithResponseBodyLimit(context.Background(), 0)
+	body, err := client.makeRequestContext(ctx, http.MethodGet, "/logs/tail", nil)
+	assert.NoError(t, err)
+	assert.Len(t, body, 1024)
+}
+
+func TestTransportLimits_DefaultsFillZeroFields(t *testing.T) {
+	limits := fillTransportLimitDefaults(TransportLimits{})
+	assert.Equal(t, defaultTransportLimits, limits)
+}
+
+func TestTransportLimits_PreservesDefaultTransportProxy(t *testing.T) {
+	api := &API{}
+	err := UsingTransportLimits(TransportLimits{})(api)
+	assert.NoError(t, err)
+
+	transport, ok := api.httpClient.Transport.(*http.Transport)
+	assert.True(t, ok)
+	assert.NotNil(t, transport.Proxy, "UsingTransportLimits must not drop http.DefaultTransport's Proxy")
+	assert.NotNil(t, transport.DialContext, "UsingTransportLimits must not drop http.DefaultTransport's DialContext")
+}
@@ -0,0 +1,37 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// User describes a Cloudflare user.
+type User struct {
+	ID       string `json:"id,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// UserResponse wraps the API envelope around a single User.
+type UserResponse struct {
+	Response
+	Result User `json:"result"`
+}
+
+// UserDetails provides information about the user associated with the
+// current API credentials.
+func (api *API) UserDetails(ctx context.Context) (User, error) {
+	res, err := api.makeRequestContext(ctx, http.MethodGet, "/user", nil)
+	if err != nil {
+		return User{}, err
+	}
+
+	var r UserResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return User{}, fmt.Errorf("cloudflare: error unmarshalling user details: %w", err)
+	}
+
+	return r.Result, nil
+}